@@ -0,0 +1,91 @@
+package sling
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Stop is returned by a BackoffPolicy's NextBackOff to indicate that no more
+// retries should be attempted.
+const Stop time.Duration = -1
+
+// BackoffPolicy computes the wait interval between retry attempts.
+// NextBackOff is called once per failed attempt and returns Stop once the
+// policy has given up. Reset restores the policy to its initial state and
+// should be called before a new sequence of retries begins.
+type BackoffPolicy interface {
+	NextBackOff() time.Duration
+	Reset()
+}
+
+// Default tuning parameters for NewExponentialBackOff.
+const (
+	DefaultInitialInterval     = 500 * time.Millisecond
+	DefaultRandomizationFactor = 0.5
+	DefaultMultiplier          = 1.5
+	DefaultMaxInterval         = 60 * time.Second
+	DefaultMaxElapsedTime      = 15 * time.Minute
+)
+
+// ExponentialBackOff is a BackoffPolicy whose wait interval grows
+// exponentially between attempts, with random jitter applied to avoid
+// synchronized retries across clients. It gives up, returning Stop, once
+// MaxElapsedTime has passed since the last Reset. A zero MaxElapsedTime or
+// MaxInterval disables that limit.
+type ExponentialBackOff struct {
+	InitialInterval     time.Duration
+	RandomizationFactor float64
+	Multiplier          float64
+	MaxInterval         time.Duration
+	MaxElapsedTime      time.Duration
+
+	currentInterval time.Duration
+	startTime       time.Time
+}
+
+// NewExponentialBackOff returns an ExponentialBackOff configured with the
+// package's Default* constants, ready to use.
+func NewExponentialBackOff() *ExponentialBackOff {
+	b := &ExponentialBackOff{
+		InitialInterval:     DefaultInitialInterval,
+		RandomizationFactor: DefaultRandomizationFactor,
+		Multiplier:          DefaultMultiplier,
+		MaxInterval:         DefaultMaxInterval,
+		MaxElapsedTime:      DefaultMaxElapsedTime,
+	}
+	b.Reset()
+	return b
+}
+
+// Reset restores the current interval to InitialInterval and restarts the
+// MaxElapsedTime clock from now.
+func (b *ExponentialBackOff) Reset() {
+	b.currentInterval = b.InitialInterval
+	b.startTime = time.Now()
+}
+
+// NextBackOff returns the jittered duration to wait before the next retry,
+// or Stop if MaxElapsedTime has elapsed since the last Reset.
+func (b *ExponentialBackOff) NextBackOff() time.Duration {
+	if b.MaxElapsedTime != 0 && time.Since(b.startTime) > b.MaxElapsedTime {
+		return Stop
+	}
+	next := b.jitter(b.currentInterval)
+	b.currentInterval = time.Duration(float64(b.currentInterval) * b.Multiplier)
+	if b.MaxInterval != 0 && b.currentInterval > b.MaxInterval {
+		b.currentInterval = b.MaxInterval
+	}
+	return next
+}
+
+// jitter returns a value randomly distributed within
+// interval ± interval*RandomizationFactor.
+func (b *ExponentialBackOff) jitter(interval time.Duration) time.Duration {
+	if b.RandomizationFactor <= 0 {
+		return interval
+	}
+	delta := b.RandomizationFactor * float64(interval)
+	min := float64(interval) - delta
+	max := float64(interval) + delta
+	return time.Duration(min + rand.Float64()*(max-min))
+}
@@ -0,0 +1,112 @@
+package sling
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// Decoding
+
+// Decoder decodes an HTTP response body into v. The caller is responsible
+// for closing resp.Body; Decode should only read from it.
+type Decoder interface {
+	Decode(resp *http.Response, v interface{}) error
+}
+
+// ResponseDecoder sets the Decoder used by ReceiveInto and DoInto. Without
+// one, the decoder is inferred from the response's Content-Type header,
+// falling back to JSONDecoder.
+func (s *Sling) ResponseDecoder(decoder Decoder) *Sling {
+	s.decoder = decoder
+	return s
+}
+
+// JSONDecoder decodes a response body as JSON.
+type JSONDecoder struct{}
+
+// Decode JSON decodes resp.Body into v.
+func (JSONDecoder) Decode(resp *http.Response, v interface{}) error {
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// XMLDecoder decodes a response body as XML.
+type XMLDecoder struct{}
+
+// Decode XML decodes resp.Body into v.
+func (XMLDecoder) Decode(resp *http.Response, v interface{}) error {
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// FormDecoder decodes an application/x-www-form-urlencoded response body.
+type FormDecoder struct{}
+
+// Decode parses resp.Body as url-encoded form data into v, which must be a
+// *url.Values.
+func (FormDecoder) Decode(resp *http.Response, v interface{}) error {
+	values, ok := v.(*url.Values)
+	if !ok {
+		return fmt.Errorf("sling: FormDecoder requires a *url.Values, got %T", v)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	parsed, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	*values = parsed
+	return nil
+}
+
+// decoderFor returns the Decoder matching a Content-Type header value,
+// defaulting to JSONDecoder when the type is unrecognized or absent.
+func decoderFor(contentTypeHeader string) Decoder {
+	mt, _, _ := mime.ParseMediaType(contentTypeHeader)
+	switch mt {
+	case formContentType:
+		return FormDecoder{}
+	case "application/xml", "text/xml":
+		return XMLDecoder{}
+	default:
+		return JSONDecoder{}
+	}
+}
+
+// ReceiveInto creates a new HTTP request, sends it, and decodes the
+// response: success for a 2xx status, failure otherwise. Either may be nil
+// to skip decoding for that case. ReceiveInto is shorthand for calling
+// Request and DoInto.
+func (s *Sling) ReceiveInto(success, failure interface{}) (*http.Response, error) {
+	req, err := s.Request()
+	if err != nil {
+		return nil, err
+	}
+	return s.DoInto(req, success, failure)
+}
+
+// DoInto sends req and decodes the response the same way ReceiveInto does.
+func (s *Sling) DoInto(req *http.Request, success, failure interface{}) (*http.Response, error) {
+	resp, err := s.send(req)
+	if err != nil {
+		return resp, err
+	}
+	defer resp.Body.Close()
+	v := success
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		v = failure
+	}
+	if v == nil {
+		return resp, nil
+	}
+	decoder := s.decoder
+	if decoder == nil {
+		decoder = decoderFor(resp.Header.Get(contentType))
+	}
+	return resp, decoder.Decode(resp, v)
+}
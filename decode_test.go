@@ -0,0 +1,78 @@
+package sling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type decodeTestModel struct {
+	Name string `json:"name"`
+}
+
+type decodeTestError struct {
+	Message string `json:"message"`
+}
+
+func TestReceiveIntoRoutesByStatusClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(contentType, jsonContentType)
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"name":"gopher"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"bad request"}`))
+	}))
+	defer server.Close()
+
+	var success decodeTestModel
+	var failure decodeTestError
+	resp, err := New().Get(server.URL+"/ok").ReceiveInto(&success, &failure)
+	if err != nil {
+		t.Fatalf("ReceiveInto: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if success.Name != "gopher" {
+		t.Errorf("success.Name = %q, want %q", success.Name, "gopher")
+	}
+	if failure.Message != "" {
+		t.Errorf("failure.Message = %q, want empty (2xx should not decode into failure)", failure.Message)
+	}
+
+	success = decodeTestModel{}
+	failure = decodeTestError{}
+	resp, err = New().Get(server.URL+"/bad").ReceiveInto(&success, &failure)
+	if err != nil {
+		t.Fatalf("ReceiveInto: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+	if failure.Message != "bad request" {
+		t.Errorf("failure.Message = %q, want %q", failure.Message, "bad request")
+	}
+	if success.Name != "" {
+		t.Errorf("success.Name = %q, want empty (4xx should not decode into success)", success.Name)
+	}
+}
+
+func TestDecoderForSelectsByContentType(t *testing.T) {
+	cases := []struct {
+		contentTypeHeader string
+		want              Decoder
+	}{
+		{jsonContentType, JSONDecoder{}},
+		{"application/xml", XMLDecoder{}},
+		{formContentType, FormDecoder{}},
+		{"", JSONDecoder{}},
+	}
+	for _, c := range cases {
+		if got := decoderFor(c.contentTypeHeader); got != c.want {
+			t.Errorf("decoderFor(%q) = %#v, want %#v", c.contentTypeHeader, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package sling
+
+import "io"
+
+// Encoding
+
+// BodyProvider supplies a request body and its Content-Type, generalizing
+// the BodyJSON/BodyForm shortcuts to custom encodings such as protobuf or
+// msgpack. It is the request-side counterpart to Decoder: there is no
+// separately named RequestEncoder type, BodyProvider fills that role.
+type BodyProvider interface {
+	// ContentType returns the value to set on the request's Content-Type
+	// header, or "" to leave it unset.
+	ContentType() string
+	// Body returns the request body reader. It is called once per Request(),
+	// and again for each retry attempt when a BackoffPolicy is set.
+	Body() (io.Reader, error)
+}
+
+// BodyProvider sets the Sling's BodyProvider. ContentType() is applied to
+// the header immediately; Body() is called when the request is built (see
+// Request()) and again before each retry attempt.
+func (s *Sling) BodyProvider(body BodyProvider) *Sling {
+	if body == nil {
+		return s
+	}
+	s.bodyProvider = body
+	if ct := body.ContentType(); ct != "" {
+		s.Set(contentType, ct)
+	}
+	return s
+}
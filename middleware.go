@@ -0,0 +1,259 @@
+package sling
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Middleware
+
+// DoerMiddleware wraps a Doer to add behavior such as logging, retries, or
+// rate limiting, matching the "stack of client-side middleware" pattern
+// described on Doer.
+type DoerMiddleware func(Doer) Doer
+
+// Use appends middleware to the Doer stack used by Do. Middlewares run in
+// registration order: the first one passed wraps everything after it, so it
+// sees the request first and the response last. New copies the stack, so
+// child Slings inherit it like headers and query structs do.
+func (s *Sling) Use(middleware ...DoerMiddleware) *Sling {
+	s.middleware = append(s.middleware, middleware...)
+	return s
+}
+
+// doerFunc adapts a function to the Doer interface.
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// LoggingMiddleware returns a DoerMiddleware that logs each request's method
+// and URL before sending and its status and latency after. No headers are
+// logged. A nil logger defaults to log.Default().
+func LoggingMiddleware(logger *log.Logger) DoerMiddleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next Doer) Doer {
+		return doerFunc(func(req *http.Request) (*http.Response, error) {
+			logger.Printf("--> %s %s", req.Method, req.URL)
+			start := time.Now()
+			resp, err := next.Do(req)
+			if err != nil {
+				logger.Printf("<-- %s %s error: %v (%s)", req.Method, req.URL, err, time.Since(start))
+				return resp, err
+			}
+			logger.Printf("<-- %s %s %s (%s)", req.Method, req.URL, resp.Status, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// GzipMiddleware returns a DoerMiddleware that advertises gzip support via
+// Accept-Encoding and transparently decompresses a gzip-encoded response
+// body, so callers never see Content-Encoding: gzip.
+func GzipMiddleware() DoerMiddleware {
+	return func(next Doer) Doer {
+		return doerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Accept-Encoding") == "" {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			resp, err := next.Do(req)
+			if err != nil || resp == nil || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+				return resp, err
+			}
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			resp.Body = &gzipBody{gz: gz, orig: resp.Body}
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+			resp.ContentLength = -1
+			return resp, nil
+		})
+	}
+}
+
+// gzipBody decompresses a gzip-encoded response body on Read and closes both
+// the gzip.Reader and the underlying body on Close.
+type gzipBody struct {
+	gz   *gzip.Reader
+	orig io.ReadCloser
+}
+
+func (g *gzipBody) Read(p []byte) (int, error) { return g.gz.Read(p) }
+
+func (g *gzipBody) Close() error {
+	gzErr := g.gz.Close()
+	origErr := g.orig.Close()
+	if gzErr != nil {
+		return gzErr
+	}
+	return origErr
+}
+
+// TimeoutMiddleware returns a DoerMiddleware that bounds each request to
+// timeout, canceling it if no response headers arrive in time. The deadline
+// is extended to cover reading the response body.
+func TimeoutMiddleware(timeout time.Duration) DoerMiddleware {
+	return func(next Doer) Doer {
+		return doerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			resp, err := next.Do(req.WithContext(ctx))
+			if err != nil {
+				cancel()
+				return resp, err
+			}
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		})
+	}
+}
+
+// cancelOnCloseBody releases a context's resources once the body that was
+// read under it is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnCloseBody) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// CircuitBreakerState is the operating state of a circuit breaker installed
+// by CircuitBreakerMiddleware.
+type CircuitBreakerState int
+
+// States a circuit breaker can be in.
+const (
+	CircuitClosed CircuitBreakerState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// CircuitBreakerMiddleware returns a DoerMiddleware implementing a circuit
+// breaker. After failureThreshold consecutive failures (a network error or
+// a 5xx response) the circuit opens and fails fast for cooldown; it then
+// allows one half-open trial request, closing on success or reopening on
+// failure.
+func CircuitBreakerMiddleware(failureThreshold int, cooldown time.Duration) DoerMiddleware {
+	cb := &circuitBreaker{threshold: failureThreshold, cooldown: cooldown}
+	return func(next Doer) Doer {
+		return doerFunc(func(req *http.Request) (*http.Response, error) {
+			return cb.do(next, req)
+		})
+	}
+}
+
+// circuitBreaker holds the mutable state behind CircuitBreakerMiddleware.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     CircuitBreakerState
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+}
+
+func (cb *circuitBreaker) do(next Doer, req *http.Request) (*http.Response, error) {
+	cb.mu.Lock()
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			cb.mu.Unlock()
+			return nil, fmt.Errorf("sling: circuit breaker open for %s", req.URL)
+		}
+		// Cooldown elapsed: this caller alone gets the half-open trial. Flip
+		// the state before unlocking so every other concurrent caller below
+		// observes CircuitHalfOpen and fails fast instead of racing in too.
+		cb.state = CircuitHalfOpen
+	case CircuitHalfOpen:
+		cb.mu.Unlock()
+		return nil, fmt.Errorf("sling: circuit breaker half-open trial already in flight for %s", req.URL)
+	}
+	cb.mu.Unlock()
+
+	resp, err := next.Do(req)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if err != nil || (resp != nil && resp.StatusCode >= 500) {
+		cb.failures++
+		if cb.state == CircuitHalfOpen || cb.failures >= cb.threshold {
+			cb.state = CircuitOpen
+			cb.openedAt = time.Now()
+		}
+		return resp, err
+	}
+	cb.failures = 0
+	cb.state = CircuitClosed
+	return resp, err
+}
+
+// RateLimiterMiddleware returns a DoerMiddleware that throttles requests to
+// a token-bucket rate of ratePerSecond, with capacity for burst requests
+// sent back-to-back. Do blocks until a token is available or the request's
+// context is done.
+func RateLimiterMiddleware(ratePerSecond float64, burst int) DoerMiddleware {
+	tb := newTokenBucket(ratePerSecond, burst)
+	return func(next Doer) Doer {
+		return doerFunc(func(req *http.Request) (*http.Response, error) {
+			if err := tb.wait(req.Context()); err != nil {
+				return nil, err
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// tokenBucket is a simple token-bucket rate limiter.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, capacity: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until a token is available, refilling the bucket based on
+// elapsed time, or returns ctx.Err() if ctx is done first.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package sling
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var inFlight int32
+	recordingDoer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		return http.DefaultClient.Do(req)
+	})
+
+	breaker := CircuitBreakerMiddleware(2, 20*time.Millisecond)
+	doer := breaker(recordingDoer)
+
+	req := func() *http.Request {
+		r, _ := http.NewRequest("GET", server.URL, nil)
+		return r
+	}
+
+	// Two failures trip the breaker (threshold=2).
+	for i := 0; i < 2; i++ {
+		resp, err := doer.Do(req())
+		if err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	// Circuit is now open: the next call must fail fast without reaching
+	// the wrapped Doer.
+	if _, err := doer.Do(req()); err == nil {
+		t.Fatal("expected circuit breaker to fail fast while open")
+	}
+
+	time.Sleep(30 * time.Millisecond) // let cooldown elapse
+
+	// Fire a burst of concurrent requests once cooldown has elapsed: only
+	// one half-open trial may reach the wrapped Doer at a time.
+	var wg sync.WaitGroup
+	var maxInFlight int32
+	var sampled int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := doer.Do(req())
+			if err == nil {
+				resp.Body.Close()
+			}
+			if n := atomic.LoadInt32(&inFlight); n > atomic.LoadInt32(&maxInFlight) {
+				atomic.StoreInt32(&maxInFlight, n)
+			}
+			atomic.AddInt32(&sampled, 1)
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > 1 {
+		t.Errorf("max concurrent half-open trials reaching the Doer = %d, want <= 1", got)
+	}
+}
+
+func TestCircuitBreakerClosesOnTrialSuccess(t *testing.T) {
+	var fail int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	breaker := CircuitBreakerMiddleware(1, 10*time.Millisecond)
+	doer := breaker(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}))
+	req := func() *http.Request {
+		r, _ := http.NewRequest("GET", server.URL, nil)
+		return r
+	}
+
+	resp, err := doer.Do(req())
+	if err != nil {
+		t.Fatalf("tripping failure: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := doer.Do(req()); err == nil {
+		t.Fatal("expected circuit to be open immediately after tripping")
+	}
+
+	atomic.StoreInt32(&fail, 0)
+	time.Sleep(15 * time.Millisecond)
+
+	resp, err = doer.Do(req())
+	if err != nil {
+		t.Fatalf("half-open trial: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("trial status = %d, want 200", resp.StatusCode)
+	}
+
+	// Circuit should now be closed: a normal request goes straight through.
+	resp, err = doer.Do(req())
+	if err != nil {
+		t.Fatalf("post-recovery request: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestGzipMiddlewareReturnsNilResponseOnBadBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("not actually gzip"))
+	}))
+	defer server.Close()
+
+	doer := GzipMiddleware()(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}))
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := doer.Do(req)
+	if err == nil {
+		t.Fatal("expected an error decoding a corrupt gzip body")
+	}
+	if resp != nil {
+		t.Errorf("expected a nil response on gzip decode failure, got %+v", resp)
+	}
+}
+
+func TestLoggingMiddlewareLogsRequestAndResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var buf strings.Builder
+	logger := log.New(&buf, "", 0)
+	doer := LoggingMiddleware(logger)(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	out := buf.String()
+	if !strings.Contains(out, "GET") || !strings.Contains(out, server.URL) {
+		t.Errorf("log output = %q, want it to mention method and URL", out)
+	}
+	if !strings.Contains(out, "200") {
+		t.Errorf("log output = %q, want it to mention the response status", out)
+	}
+}
+
+// TestTimeoutMiddlewareCancelsSlowRequest guards against TimeoutMiddleware
+// silently failing to bound a request: a handler that sleeps past timeout
+// must cause Do to return a context deadline error instead of waiting it
+// out.
+func TestTimeoutMiddlewareCancelsSlowRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	doer := TimeoutMiddleware(10 * time.Millisecond)(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	start := time.Now()
+	_, err := doer.Do(req)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected an error from a request exceeding the timeout")
+	}
+	if elapsed > 80*time.Millisecond {
+		t.Errorf("Do took %s, want it to have been canceled well before the handler's 100ms sleep", elapsed)
+	}
+}
+
+// TestRateLimiterMiddlewareBlocksUntilTokenAvailable confirms the limiter
+// actually throttles: with a burst of 1 and a slow refill rate, a second
+// back-to-back request must wait for a token rather than going straight
+// through.
+func TestRateLimiterMiddlewareBlocksUntilTokenAvailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	doer := RateLimiterMiddleware(10, 1)(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}))
+	req := func() *http.Request {
+		r, _ := http.NewRequest("GET", server.URL, nil)
+		return r
+	}
+
+	if _, err := doer.Do(req()); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := doer.Do(req()); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("second request returned after %s, want it blocked for a refill at 10/s burst 1", elapsed)
+	}
+}
+
+// TestRateLimiterMiddlewareRespectsContextCancellation ensures wait does not
+// block forever once its request's context is canceled while still waiting
+// for a token.
+func TestRateLimiterMiddlewareRespectsContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	doer := RateLimiterMiddleware(1, 1)(doerFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultClient.Do(req)
+	}))
+	req := func() *http.Request {
+		r, _ := http.NewRequest("GET", server.URL, nil)
+		return r
+	}
+
+	if _, err := doer.Do(req()); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Millisecond)
+	defer cancel()
+	r, _ := http.NewRequest("GET", server.URL, nil)
+	r = r.WithContext(ctx)
+
+	start := time.Now()
+	_, err := doer.Do(r)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected context deadline error while waiting for a token")
+	}
+	if elapsed > 100*time.Millisecond {
+		t.Errorf("Do took %s to return after context cancellation, want it to return promptly", elapsed)
+	}
+}
@@ -0,0 +1,142 @@
+package sling
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// Multipart
+
+// BodyMultipart returns a MultipartBuilder for constructing a
+// multipart/form-data request body, and registers it as the Sling's
+// BodyProvider. Because the builder is a BodyProvider, sendWithRetry calls
+// Body() again for every retry attempt rather than replaying a single
+// buffered copy, so uploads attached via AddFilePath are re-read from disk
+// on each attempt and those attached via AddFile are replayed from the
+// buffered copy taken when they were added.
+func (s *Sling) BodyMultipart() *MultipartBuilder {
+	b := &MultipartBuilder{boundary: randomBoundary()}
+	s.BodyProvider(b)
+	return b
+}
+
+// multipartPart is either a plain form field or a file part.
+type multipartPart struct {
+	field    string
+	value    string
+	filename string
+	path     string
+	data     []byte
+	isFile   bool
+}
+
+// MultipartBuilder builds a multipart/form-data request body field by
+// field. Methods return the builder so calls can be chained.
+type MultipartBuilder struct {
+	boundary string
+	parts    []multipartPart
+	err      error
+}
+
+// AddField adds a plain form field.
+func (b *MultipartBuilder) AddField(name, value string) *MultipartBuilder {
+	b.parts = append(b.parts, multipartPart{field: name, value: value})
+	return b
+}
+
+// AddFile adds a file part read from r under the given form field and
+// filename. r is read and buffered immediately so the part can be replayed
+// if the request is retried.
+func (b *MultipartBuilder) AddFile(fieldName, filename string, r io.Reader) *MultipartBuilder {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.parts = append(b.parts, multipartPart{field: fieldName, filename: filename, data: data, isFile: true})
+	return b
+}
+
+// AddFilePath adds a file part whose content is read from path. Unlike
+// AddFile, the file is opened fresh each time the body is built, so large
+// files are streamed rather than buffered in memory.
+func (b *MultipartBuilder) AddFilePath(fieldName, path string) *MultipartBuilder {
+	b.parts = append(b.parts, multipartPart{field: fieldName, filename: filepath.Base(path), path: path, isFile: true})
+	return b
+}
+
+// ContentType returns the multipart/form-data content type carrying this
+// builder's boundary.
+func (b *MultipartBuilder) ContentType() string {
+	return "multipart/form-data; boundary=" + b.boundary
+}
+
+// Body streams the builder's fields and files as a multipart/form-data
+// message, writing through an io.Pipe so large files need not be buffered
+// whole.
+func (b *MultipartBuilder) Body() (io.Reader, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	if err := mw.SetBoundary(b.boundary); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := b.writeParts(mw)
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// writeParts writes every field and file part to mw in the order they were
+// added.
+func (b *MultipartBuilder) writeParts(mw *multipart.Writer) error {
+	for _, p := range b.parts {
+		if !p.isFile {
+			if err := mw.WriteField(p.field, p.value); err != nil {
+				return err
+			}
+			continue
+		}
+		part, err := mw.CreateFormFile(p.field, p.filename)
+		if err != nil {
+			return err
+		}
+		if p.path != "" {
+			if err := copyFilePath(part, p.path); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := part.Write(p.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFilePath opens path fresh and copies its content into dst.
+func copyFilePath(dst io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(dst, f)
+	return err
+}
+
+// randomBoundary returns a fresh multipart boundary, generated the same way
+// mime/multipart.NewWriter does.
+func randomBoundary() string {
+	return multipart.NewWriter(&bytes.Buffer{}).Boundary()
+}
@@ -0,0 +1,180 @@
+package sling
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func readMultipart(t *testing.T, contentTypeHeader string, body []byte) *multipart.Reader {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentTypeHeader)
+	if err != nil {
+		t.Fatalf("parsing content type %q: %v", contentTypeHeader, err)
+	}
+	return multipart.NewReader(bytes.NewReader(body), params["boundary"])
+}
+
+func TestMultipartBuilderWritesFieldsAndFiles(t *testing.T) {
+	b := &MultipartBuilder{boundary: randomBoundary()}
+	b.AddField("name", "gopher").AddFile("file", "hello.txt", strings.NewReader("hello world"))
+
+	r, err := b.Body()
+	if err != nil {
+		t.Fatalf("Body: %v", err)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+
+	mr := readMultipart(t, b.ContentType(), data)
+	part, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("first part: %v", err)
+	}
+	if part.FormName() != "name" {
+		t.Errorf("first part name = %q, want %q", part.FormName(), "name")
+	}
+	val, _ := ioutil.ReadAll(part)
+	if string(val) != "gopher" {
+		t.Errorf("first part value = %q, want %q", val, "gopher")
+	}
+
+	part, err = mr.NextPart()
+	if err != nil {
+		t.Fatalf("second part: %v", err)
+	}
+	if part.FormName() != "file" || part.FileName() != "hello.txt" {
+		t.Errorf("second part = (%q, %q), want (%q, %q)", part.FormName(), part.FileName(), "file", "hello.txt")
+	}
+	content, _ := ioutil.ReadAll(part)
+	if string(content) != "hello world" {
+		t.Errorf("file content = %q, want %q", content, "hello world")
+	}
+}
+
+// TestMultipartBuilderBodyIsReplayable ensures Body() can be called more
+// than once and produce identical content each time, which is what a retry
+// relies on when replaying an AddFile upload.
+func TestMultipartBuilderBodyIsReplayable(t *testing.T) {
+	b := &MultipartBuilder{boundary: randomBoundary()}
+	b.AddField("a", "1").AddFile("file", "f.txt", strings.NewReader("payload"))
+
+	first, err := b.Body()
+	if err != nil {
+		t.Fatalf("first Body: %v", err)
+	}
+	firstData, err := ioutil.ReadAll(first)
+	if err != nil {
+		t.Fatalf("reading first body: %v", err)
+	}
+
+	second, err := b.Body()
+	if err != nil {
+		t.Fatalf("second Body: %v", err)
+	}
+	secondData, err := ioutil.ReadAll(second)
+	if err != nil {
+		t.Fatalf("reading second body: %v", err)
+	}
+
+	if !bytes.Equal(firstData, secondData) {
+		t.Errorf("replayed body differs:\nfirst:  %q\nsecond: %q", firstData, secondData)
+	}
+}
+
+// TestSlingRetriesMultipartBody drives a BodyMultipart request through a
+// server that fails once, confirming the retried attempt resends the full
+// multipart body rather than an empty or truncated one.
+func TestSlingRetriesMultipartBody(t *testing.T) {
+	var attempts int32
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		lastBody = body
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	policy := &ExponentialBackOff{InitialInterval: time.Millisecond, Multiplier: 1, MaxElapsedTime: time.Second}
+	s := New().Post(server.URL).Retry(policy)
+	s.BodyMultipart().AddField("name", "gopher").AddFile("file", "hello.txt", strings.NewReader("hello world"))
+
+	_, resp, err := s.Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if !bytes.Contains(lastBody, []byte("hello world")) {
+		t.Errorf("retried request body missing file content: %q", lastBody)
+	}
+	if !bytes.Contains(lastBody, []byte("gopher")) {
+		t.Errorf("retried request body missing field content: %q", lastBody)
+	}
+}
+
+// TestSlingRetriesAddFilePathFromDisk drives a BodyMultipart request using
+// AddFilePath through a server that fails once, then rewrites the file on
+// disk before the retry. The retried attempt must send the rewritten
+// content, proving the file is reopened and streamed fresh on each attempt
+// rather than buffered into memory on the first build.
+func TestSlingRetriesAddFilePathFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "upload.txt")
+	if err := os.WriteFile(path, []byte("original content"), 0o600); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	var attempts int32
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		lastBody = body
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			if err := os.WriteFile(path, []byte("rewritten content"), 0o600); err != nil {
+				t.Fatalf("rewriting file: %v", err)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	policy := &ExponentialBackOff{InitialInterval: time.Millisecond, Multiplier: 1, MaxElapsedTime: time.Second}
+	s := New().Post(server.URL).Retry(policy)
+	s.BodyMultipart().AddFilePath("file", path)
+
+	_, resp, err := s.Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if !bytes.Contains(lastBody, []byte("rewritten content")) {
+		t.Errorf("retried request body = %q, want it to contain the file's rewritten content: AddFilePath must reopen the file per attempt, not replay a buffered copy", lastBody)
+	}
+}
@@ -0,0 +1,238 @@
+package sling
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signing
+
+// RequestSigner signs an *http.Request in place, typically by adding an
+// Authorization header. Sign is invoked by Do once the request's URL, query,
+// and body are fully built, and again on every retry attempt so
+// time-sensitive signatures (nonces, timestamps) stay fresh.
+type RequestSigner interface {
+	Sign(req *http.Request) error
+}
+
+// Sign sets the RequestSigner used to sign requests made by Do and Receive.
+func (s *Sling) Sign(signer RequestSigner) *Sling {
+	s.signer = signer
+	return s
+}
+
+// OAuth1SignatureMethod identifies the signature algorithm an OAuth1Signer
+// uses to sign requests.
+type OAuth1SignatureMethod string
+
+// Signature methods supported by OAuth1Signer.
+const (
+	HMACSHA1   OAuth1SignatureMethod = "HMAC-SHA1"
+	HMACSHA256 OAuth1SignatureMethod = "HMAC-SHA256"
+	PLAINTEXT  OAuth1SignatureMethod = "PLAINTEXT"
+)
+
+// OAuth1Signer is a RequestSigner implementing OAuth 1.0a request signing
+// (RFC 5849). It adds an "Authorization: OAuth ..." header whose signature
+// covers the HTTP method, URL, query parameters, OAuth parameters, and, for
+// an application/x-www-form-urlencoded body, the body parameters.
+type OAuth1Signer struct {
+	ConsumerKey    string
+	ConsumerSecret string
+	Token          string
+	TokenSecret    string
+	// SignatureMethod selects the signing algorithm. It defaults to
+	// HMACSHA1 when left empty.
+	SignatureMethod OAuth1SignatureMethod
+}
+
+// Sign adds a fresh OAuth 1.0a Authorization header to req.
+func (s *OAuth1Signer) Sign(req *http.Request) error {
+	method := s.SignatureMethod
+	if method == "" {
+		method = HMACSHA1
+	}
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     s.ConsumerKey,
+		"oauth_nonce":            nonce(),
+		"oauth_signature_method": string(method),
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+	if s.Token != "" {
+		oauthParams["oauth_token"] = s.Token
+	}
+	bodyParams, err := formBodyParams(req)
+	if err != nil {
+		return err
+	}
+	signature, err := s.signature(req, method, oauthParams, bodyParams)
+	if err != nil {
+		return err
+	}
+	oauthParams["oauth_signature"] = signature
+	req.Header.Set("Authorization", oauthHeader(oauthParams))
+	return nil
+}
+
+// signature computes the OAuth1 signature for req under the given method.
+func (s *OAuth1Signer) signature(req *http.Request, method OAuth1SignatureMethod, oauthParams map[string]string, bodyParams url.Values) (string, error) {
+	key := percentEncode(s.ConsumerSecret) + "&" + percentEncode(s.TokenSecret)
+	if method == PLAINTEXT {
+		return key, nil
+	}
+	var newHash func() hash.Hash
+	switch method {
+	case HMACSHA1:
+		newHash = sha1.New
+	case HMACSHA256:
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("sling: unsupported oauth1 signature method %q", method)
+	}
+	base := signatureBase(req, oauthParams, bodyParams)
+	mac := hmac.New(newHash, []byte(key))
+	mac.Write([]byte(base))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// signatureBase builds the RFC 5849 signature base string:
+// METHOD&percent-encoded-URL&percent-encoded-params.
+func signatureBase(req *http.Request, oauthParams map[string]string, bodyParams url.Values) string {
+	params := url.Values{}
+	for k, v := range oauthParams {
+		params.Set(k, v)
+	}
+	for k, vs := range req.URL.Query() {
+		for _, v := range vs {
+			params.Add(k, v)
+		}
+	}
+	for k, vs := range bodyParams {
+		for _, v := range vs {
+			params.Add(k, v)
+		}
+	}
+	return strings.ToUpper(req.Method) + "&" + percentEncode(baseURL(req)) + "&" + percentEncode(canonicalParams(params))
+}
+
+// baseURL returns req's URL with the query and fragment stripped and the
+// scheme and host lowercased, per RFC 5849's base string URI.
+func baseURL(req *http.Request) string {
+	u := *req.URL
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// canonicalParams percent-encodes and sorts params into the canonical
+// "key=value" parameter string used in the signature base string.
+func canonicalParams(params url.Values) string {
+	type pair struct{ key, value string }
+	pairs := make([]pair, 0, len(params))
+	for k, vs := range params {
+		for _, v := range vs {
+			pairs = append(pairs, pair{percentEncode(k), percentEncode(v)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].key != pairs[j].key {
+			return pairs[i].key < pairs[j].key
+		}
+		return pairs[i].value < pairs[j].value
+	})
+	parts := make([]string, len(pairs))
+	for i, p := range pairs {
+		parts[i] = p.key + "=" + p.value
+	}
+	return strings.Join(parts, "&")
+}
+
+// formBodyParams returns the parsed body of req if it is
+// application/x-www-form-urlencoded and req.GetBody can replay it, restoring
+// req.Body afterwards so it can still be sent. Any other body, including a
+// form body without GetBody, is not covered by the OAuth1 signature.
+func formBodyParams(req *http.Request) (url.Values, error) {
+	mt, _, _ := mime.ParseMediaType(req.Header.Get(contentType))
+	if mt != formContentType || req.GetBody == nil {
+		return url.Values{}, nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if req.Body, err = req.GetBody(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// oauthHeader formats params as an "OAuth k1="v1", k2="v2"" Authorization
+// header value, sorted by key for determinism.
+func oauthHeader(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf(`%s="%s"`, percentEncode(k), percentEncode(params[k]))
+	}
+	return "OAuth " + strings.Join(parts, ", ")
+}
+
+// nonce returns a random, URL-safe string unique enough to satisfy
+// oauth_nonce's replay-protection requirement.
+func nonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// percentEncode percent-encodes s per RFC 3986 as required by RFC 5849,
+// leaving only unreserved characters (ALPHA / DIGIT / "-" / "." / "_" / "~")
+// unescaped.
+func percentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
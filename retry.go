@@ -0,0 +1,76 @@
+package sling
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry
+
+// Retry sets the BackoffPolicy used to retry failed requests made by Do and
+// Receive. Without a policy, requests are sent exactly once. Pass nil to
+// disable retries.
+func (s *Sling) Retry(policy BackoffPolicy) *Sling {
+	s.retryPolicy = policy
+	return s
+}
+
+// RetryOn overrides the predicate used to decide whether a failed attempt
+// should be retried. shouldRetry receives the *http.Response and error
+// returned by the underlying Doer (exactly one of which is non-nil) and
+// reports whether another attempt should be made. Without RetryOn, the
+// default predicate retries network errors and 429/5xx responses.
+func (s *Sling) RetryOn(shouldRetry func(*http.Response, error) bool) *Sling {
+	s.shouldRetry = shouldRetry
+	return s
+}
+
+// WithContext sets the context that governs cancellation of retried sends
+// started by Do: each attempt is sent under it, and so is the wait between
+// attempts. It only takes effect when a BackoffPolicy is set with Retry. If
+// unset, the context of the *http.Request passed to Do is used instead.
+func (s *Sling) WithContext(ctx context.Context) *Sling {
+	s.ctx = ctx
+	return s
+}
+
+// defaultShouldRetry is used by Do when no RetryOn predicate has been set.
+func defaultShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter returns the delay requested by a Retry-After header on a 429 or
+// 503 response, or 0 if the response carries none. Retry-After may be given
+// as a number of seconds or an HTTP date; a date already in the past yields 0.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
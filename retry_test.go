@@ -0,0 +1,139 @@
+package sling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingBackoff wraps a BackoffPolicy and counts NextBackOff calls, so
+// tests can tell whether the policy was actually consulted.
+type countingBackoff struct {
+	BackoffPolicy
+	calls int32
+}
+
+func (c *countingBackoff) NextBackOff() time.Duration {
+	atomic.AddInt32(&c.calls, 1)
+	return c.BackoffPolicy.NextBackOff()
+}
+
+func TestSlingRetriesOn5xxAnd429(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if n == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	policy := &ExponentialBackOff{InitialInterval: time.Millisecond, Multiplier: 1, MaxElapsedTime: time.Second}
+	_, resp, err := New().Get(server.URL).Retry(policy).Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestSlingDoesNotRetryOn4xxOtherThan429(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	policy := &ExponentialBackOff{InitialInterval: time.Millisecond, Multiplier: 1, MaxElapsedTime: time.Second}
+	_, resp, err := New().Get(server.URL).Retry(policy).Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("attempts = %d, want 1 (no retry on 404)", got)
+	}
+}
+
+// TestRetryAfterStillConsultsBackoffPolicy guards against honoring
+// Retry-After while bypassing the policy's own elapsed-time budget: a
+// server that always sends Retry-After must not be retried forever just
+// because MaxElapsedTime is tiny.
+func TestRetryAfterStillConsultsBackoffPolicy(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	inner := &ExponentialBackOff{InitialInterval: time.Millisecond, Multiplier: 1, MaxElapsedTime: 5 * time.Millisecond}
+	policy := &countingBackoff{BackoffPolicy: inner}
+
+	start := time.Now()
+	_, resp, err := New().Get(server.URL).Retry(policy).Receive()
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", resp.StatusCode)
+	}
+	if atomic.LoadInt32(&policy.calls) == 0 {
+		t.Error("BackoffPolicy.NextBackOff was never called despite Retry-After being present")
+	}
+	// The fix still honors one Retry-After wait before the policy's next
+	// NextBackOff call observes MaxElapsedTime has passed and returns Stop,
+	// so at most two requests are made; without the fix this server (which
+	// sends Retry-After on every response) would be retried indefinitely.
+	if got := atomic.LoadInt32(&attempts); got > 2 {
+		t.Errorf("attempts = %d, want <= 2: MaxElapsedTime was bypassed by Retry-After", got)
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("retries ran for %s, want bounded despite server's Retry-After: 1 on every response", elapsed)
+	}
+}
+
+// TestFinalResponseBodyIntactWhenPolicyGivesUp guards against draining and
+// closing a retryable response's body before checking whether the policy
+// will actually retry again: when NextBackOff returns Stop right after a
+// retryable 5xx, the caller gets that response back and must still be able
+// to read its body.
+func TestFinalResponseBodyIntactWhenPolicyGivesUp(t *testing.T) {
+	const errBody = `{"error":"unavailable"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(contentType, jsonContentType)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(errBody))
+	}))
+	defer server.Close()
+
+	policy := &ExponentialBackOff{InitialInterval: 3 * time.Millisecond, Multiplier: 1, MaxElapsedTime: 15 * time.Millisecond}
+	body, resp, err := New().Get(server.URL).Retry(policy).Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+	if string(body) != errBody {
+		t.Errorf("body = %q, want %q: the final response's body must survive the policy giving up", body, errBody)
+	}
+}
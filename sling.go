@@ -2,6 +2,7 @@ package sling
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	goquery "github.com/google/go-querystring/query"
 )
@@ -46,6 +48,20 @@ type Sling struct {
 	bodyForm interface{}
 	// simply assigned body
 	body io.ReadCloser
+	// backoff policy used to retry failed requests, nil disables retries
+	retryPolicy BackoffPolicy
+	// predicate deciding whether an attempt should be retried
+	shouldRetry func(*http.Response, error) bool
+	// context governing retry cancellation, set via WithContext
+	ctx context.Context
+	// signs requests in place before they are sent, set via Sign
+	signer RequestSigner
+	// supplies the request body for encodings other than JSON/form
+	bodyProvider BodyProvider
+	// decodes responses for ReceiveInto/DoInto, set via ResponseDecoder
+	decoder Decoder
+	// stack of Doer decorators applied to httpClient, set via Use
+	middleware []DoerMiddleware
 }
 
 // New returns a new Sling with an http DefaultClient.
@@ -61,9 +77,9 @@ func New() *Sling {
 // New returns a copy of a Sling for creating a new Sling with properties
 // from a parent Sling. For example,
 //
-// 	parentSling := sling.New().Client(client).Base("https://api.io/")
-// 	fooSling := parentSling.New().Get("foo/")
-// 	barSling := parentSling.New().Get("bar/")
+//	parentSling := sling.New().Client(client).Base("https://api.io/")
+//	fooSling := parentSling.New().Get("foo/")
+//	barSling := parentSling.New().Get("bar/")
 //
 // fooSling and barSling will both use the same client, but send requests to
 // https://api.io/foo/ and https://api.io/bar/ respectively.
@@ -86,6 +102,13 @@ func (s *Sling) New() *Sling {
 		bodyJSON:     s.bodyJSON,
 		bodyForm:     s.bodyForm,
 		body:         s.body,
+		retryPolicy:  s.retryPolicy,
+		shouldRetry:  s.shouldRetry,
+		ctx:          s.ctx,
+		signer:       s.signer,
+		bodyProvider: s.bodyProvider,
+		decoder:      s.decoder,
+		middleware:   append([]DoerMiddleware{}, s.middleware...),
 	}
 }
 
@@ -287,10 +310,45 @@ func (s *Sling) Request() (*http.Request, error) {
 	if err != nil {
 		return nil, err
 	}
+	if s.retryPolicy != nil && body != nil && req.GetBody == nil && s.bodyProvider == nil {
+		// http.NewRequest only populates GetBody for a handful of concrete
+		// reader types. Buffer anything else so Do can replay it on retry.
+		// A bodyProvider is excluded: sendWithRetry replays it by calling
+		// Body() again instead, so e.g. AddFilePath uploads keep streaming
+		// from disk rather than being buffered whole in memory.
+		if err = bufferRequestBody(req); err != nil {
+			return nil, err
+		}
+	}
 	addHeaders(req, s.header)
 	return req, err
 }
 
+// toReadCloser wraps r as an io.ReadCloser if it isn't already one, the same
+// way http.NewRequest treats a request body.
+func toReadCloser(r io.Reader) io.ReadCloser {
+	if rc, ok := r.(io.ReadCloser); ok {
+		return rc
+	}
+	return ioutil.NopCloser(r)
+}
+
+// bufferRequestBody reads req.Body into memory and installs a GetBody func
+// that replays it, so the request can be resent on retry.
+func bufferRequestBody(req *http.Request) error {
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(data))
+	return nil
+}
+
 // addQueryStructs parses url tagged query structs using go-querystring to
 // encode them to url.Values and format them onto the url.RawQuery. Any
 // query parsing or encoding errors are returned.
@@ -329,6 +387,11 @@ func (s *Sling) getRequestBody() (body io.Reader, err error) {
 		if err != nil {
 			return nil, err
 		}
+	} else if s.bodyProvider != nil {
+		body, err = s.bodyProvider.Body()
+		if err != nil {
+			return nil, err
+		}
 	} else if s.body != nil {
 		body = s.body
 	}
@@ -384,16 +447,118 @@ func (s *Sling) Receive() (response []byte, httpResponse *http.Response, err err
 
 // Do sends an HTTP request and returns the response.
 // Any error sending the request or decoding the response is returned.
+// If a BackoffPolicy has been set with Retry, Do re-issues the request
+// according to the policy and the configured retry predicate (see RetryOn)
+// until it succeeds, the policy returns Stop, or the Sling's context (see
+// WithContext) is done.
 func (s *Sling) Do(req *http.Request) (response []byte, httpResponse *http.Response, err error) {
-	if httpResponse, err = s.httpClient.Do(req); err != nil {
-		response = nil
-		return
+	httpResponse, err = s.send(req)
+	if err != nil {
+		return nil, httpResponse, err
 	}
 	// httpResponse contains a non-nil resp.Body which must be closed
 	defer httpResponse.Body.Close()
 	if response, err = ioutil.ReadAll(httpResponse.Body); err != nil {
-		response = nil
-		return
+		return nil, httpResponse, err
+	}
+	return response, httpResponse, nil
+}
+
+// send sends req, applying signing and retries, and returns the raw
+// *http.Response with its Body left open for the caller to read or decode.
+func (s *Sling) send(req *http.Request) (*http.Response, error) {
+	if s.retryPolicy == nil {
+		return s.sendOnce(req)
+	}
+	return s.sendWithRetry(req)
+}
+
+// sendOnce performs a single attempt, the behavior of send without retries.
+func (s *Sling) sendOnce(req *http.Request) (*http.Response, error) {
+	if s.signer != nil {
+		// Sign after the request is fully built so nonce/timestamp are fresh
+		// and the signature covers the final URL, query, and body.
+		if err := s.signer.Sign(req); err != nil {
+			return nil, err
+		}
+	}
+	return s.doer().Do(req)
+}
+
+// doer returns httpClient wrapped by the middleware stack registered with
+// Use, in registration order: the first-registered middleware is outermost,
+// seeing the request first and the response last.
+func (s *Sling) doer() Doer {
+	d := s.httpClient
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		d = s.middleware[i](d)
+	}
+	return d
+}
+
+// sendWithRetry drives the retry loop for send once a BackoffPolicy is set.
+func (s *Sling) sendWithRetry(req *http.Request) (httpResponse *http.Response, err error) {
+	s.retryPolicy.Reset()
+	shouldRetry := s.shouldRetry
+	if shouldRetry == nil {
+		shouldRetry = defaultShouldRetry
+	}
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = req.Context()
+	}
+	for attemptNum := 0; ; attemptNum++ {
+		attempt := req.Clone(ctx)
+		if attemptNum > 0 {
+			// The first attempt reuses req's original Body as cloned above.
+			// Later ones need a fresh, unconsumed body: prefer re-invoking
+			// the bodyProvider factory (e.g. AddFilePath reopens its file)
+			// over the generic buffered GetBody.
+			switch {
+			case s.bodyProvider != nil:
+				body, bodyErr := s.bodyProvider.Body()
+				if bodyErr != nil {
+					return nil, bodyErr
+				}
+				attempt.Body = toReadCloser(body)
+			case req.GetBody != nil:
+				if attempt.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		httpResponse, err = s.sendOnce(attempt)
+		if !shouldRetry(httpResponse, err) {
+			return httpResponse, err
+		}
+		// Decide whether another attempt will be made, and with what wait,
+		// before touching httpResponse's body: if the policy gives up here,
+		// httpResponse is what the caller gets back, and its body must
+		// still be open and unread.
+		retryAfterWait := retryAfter(httpResponse)
+		// Always consult the policy, even when a Retry-After header is
+		// present, so MaxElapsedTime/Stop still bound the retry sequence
+		// instead of being bypassed by a server-dictated wait.
+		policyWait := s.retryPolicy.NextBackOff()
+		if policyWait == Stop {
+			return httpResponse, err
+		}
+		wait := retryAfterWait
+		if wait == 0 {
+			wait = policyWait
+		}
+		if httpResponse != nil {
+			// Another attempt will replace httpResponse: discard and close
+			// its body now, since its connection cannot be reused otherwise.
+			io.Copy(ioutil.Discard, httpResponse.Body)
+			httpResponse.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return httpResponse, ctx.Err()
+		case <-timer.C:
+		}
 	}
-	return
 }